@@ -0,0 +1,91 @@
+package snowflakepro
+
+import (
+	"encoding/hex"
+	"encoding/json"
+)
+
+// UUIDStringSize is the length of the canonical
+// xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx text form of a SFID.
+const UUIDStringSize = 36
+
+// MarshalHex returns the SFID as a 32 character lowercase hex string.
+func (id SFID) MarshalHex() ([]byte, error) {
+	dst := make([]byte, hex.EncodedLen(len(id)))
+	hex.Encode(dst, id[:])
+	return dst, nil
+}
+
+// UnmarshalHex parses a 32 character hex encoded SFID produced by
+// MarshalHex.
+func (id *SFID) UnmarshalHex(v []byte) error {
+	if hex.DecodedLen(len(v)) != len(*id) {
+		return ErrDataSize
+	}
+	var buf [16]byte
+	if _, err := hex.Decode(buf[:], v); err != nil {
+		return ErrInvalidCharacters
+	}
+	copy((*id)[:], buf[:])
+	return nil
+}
+
+// MarshalUUIDString returns the SFID formatted as a canonical UUID string,
+// e.g. "00000000-0000-0000-0000-000000000000".
+func (id SFID) MarshalUUIDString() ([]byte, error) {
+	dst := make([]byte, UUIDStringSize)
+	hex.Encode(dst[0:8], id[0:4])
+	dst[8] = '-'
+	hex.Encode(dst[9:13], id[4:6])
+	dst[13] = '-'
+	hex.Encode(dst[14:18], id[6:8])
+	dst[18] = '-'
+	hex.Encode(dst[19:23], id[8:10])
+	dst[23] = '-'
+	hex.Encode(dst[24:36], id[10:16])
+	return dst, nil
+}
+
+// ParseUUIDString parses a canonical UUID string form of a SFID, as
+// produced by MarshalUUIDString.
+func ParseUUIDString(v string) (id SFID, err error) {
+	if len(v) != UUIDStringSize {
+		return id, ErrDataSize
+	}
+	if v[8] != '-' || v[13] != '-' || v[18] != '-' || v[23] != '-' {
+		return id, ErrInvalidCharacters
+	}
+
+	var buf [32]byte
+	copy(buf[0:8], v[0:8])
+	copy(buf[8:12], v[9:13])
+	copy(buf[12:16], v[14:18])
+	copy(buf[16:20], v[19:23])
+	copy(buf[20:32], v[24:36])
+
+	if _, err := hex.Decode(id[:], buf[:]); err != nil {
+		return SFID{}, ErrInvalidCharacters
+	}
+	return id, nil
+}
+
+// MarshalJSON implements json.Marshaler, emitting the SFID in its
+// lexicographically sortable base32 string form.
+func (id SFID) MarshalJSON() ([]byte, error) {
+	text, err := id.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(string(text))
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It accepts either the 26
+// character base32 form or the 36 character UUID string form, so SFIDs
+// drop into APIs and databases that already speak UUID.
+func (id *SFID) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	return id.UnmarshalText([]byte(s))
+}
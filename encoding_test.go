@@ -0,0 +1,99 @@
+package snowflakepro
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+)
+
+func Test_HexRoundTrip(t *testing.T) {
+	s, _ := NewSnowflakePro(100, 42)
+	id := s.Next()
+
+	hexText, _ := id.MarshalHex()
+
+	var got SFID
+	if err := got.UnmarshalHex(hexText); err != nil {
+		t.Fatal(err)
+	}
+	if got.Compare(id) != 0 {
+		t.Fatal("hex round trip mismatch", got, id)
+	}
+}
+
+func Test_UUIDStringRoundTrip(t *testing.T) {
+	s, _ := NewSnowflakePro(100, 42)
+	id := s.Next()
+
+	uuidText, _ := id.MarshalUUIDString()
+
+	got, err := ParseUUIDString(string(uuidText))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Compare(id) != 0 {
+		t.Fatal("uuid string round trip mismatch", got, id)
+	}
+}
+
+func Test_UnmarshalHexLeavesDestinationUntouchedOnError(t *testing.T) {
+	s, _ := NewSnowflakePro(100, 42)
+	original := s.Next()
+
+	got := original
+	bad := make([]byte, hex.EncodedLen(len(SFID{})))
+	for i := range bad {
+		bad[i] = '0'
+	}
+	bad[len(bad)-2], bad[len(bad)-1] = 'z', 'z' // invalid hex characters
+
+	if err := got.UnmarshalHex(bad); err != ErrInvalidCharacters {
+		t.Fatal("expected ErrInvalidCharacters, got", err)
+	}
+	if got.Compare(original) != 0 {
+		t.Fatal("expected destination to be left untouched on error", got, original)
+	}
+}
+
+func Test_UnmarshalTextAcceptsUUIDForm(t *testing.T) {
+	s, _ := NewSnowflakePro(100, 42)
+	id := s.Next()
+
+	uuidText, _ := id.MarshalUUIDString()
+
+	var got SFID
+	if err := got.UnmarshalText(uuidText); err != nil {
+		t.Fatal(err)
+	}
+	if got.Compare(id) != 0 {
+		t.Fatal("UnmarshalText uuid form mismatch", got, id)
+	}
+}
+
+func Test_JSONRoundTrip(t *testing.T) {
+	s, _ := NewSnowflakePro(100, 42)
+	id := s.Next()
+
+	data, err := json.Marshal(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got SFID
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Compare(id) != 0 {
+		t.Fatal("json round trip mismatch", got, id)
+	}
+
+	uuidText, _ := id.MarshalUUIDString()
+	quoted, _ := json.Marshal(string(uuidText))
+	var fromUUID SFID
+	if err := json.Unmarshal(quoted, &fromUUID); err != nil {
+		t.Fatal(err)
+	}
+	if fromUUID.Compare(id) != 0 {
+		t.Fatal("json uuid form round trip mismatch", fromUUID, id)
+	}
+}
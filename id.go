@@ -301,11 +301,23 @@ var dec = [...]byte{
 const EncodedSize = 26
 
 // UnmarshalText implements the encoding.TextUnmarshaler interface by
-// parsing the data as string encoded SFID.
+// parsing the data as a string encoded SFID. It accepts either the 26
+// character base32 form or the 36 character canonical UUID string form, so
+// SFIDs drop into APIs and databases that already speak UUID without a
+// wrapper type.
 //
-// ErrDataSize is returned if the len(v) is different from an encoded
-// SFID's length. Invalid encodings produce undefined SFIDs.
+// ErrDataSize is returned if len(v) doesn't match either encoded form.
+// Invalid base32 encodings produce undefined SFIDs; invalid UUID string
+// encodings return ErrInvalidCharacters.
 func (id *SFID) UnmarshalText(v []byte) error {
+	if len(v) == UUIDStringSize {
+		parsed, err := ParseUUIDString(string(v))
+		if err != nil {
+			return err
+		}
+		*id = parsed
+		return nil
+	}
 	return parse(v, false, id)
 }
 
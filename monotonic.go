@@ -0,0 +1,103 @@
+package snowflakepro
+
+import (
+	cryptoRand "crypto/rand"
+	"encoding/binary"
+	"io"
+	"sync"
+	"time"
+)
+
+// entropyBits is the width of the combined nonce+sn region used as the
+// random entropy source, i.e. the 40 bit nonce field plus the 24 bit sn
+// field.
+const entropyBits = 64
+
+// maxEntropyIncrement bounds the random step added to the previous
+// millisecond's entropy, so that successive IDs within the same
+// millisecond stay strictly increasing without the step itself leaking a
+// predictable pattern.
+const maxEntropyIncrement = 1 << 16
+
+// MonotonicSnowflakePro is a SnowflakePro variant inspired by ULID's
+// monotonic mode: instead of a fixed nonce and a predictable per-ms
+// counter, the nonce+sn region is filled with fresh randomness on every
+// new millisecond, and bumped by a small random increment when Next is
+// called again within the same millisecond. IDs stay strictly increasing
+// and lexicographically sortable, but the entropy portion no longer
+// reveals the allocation sequence.
+type MonotonicSnowflakePro struct {
+	node    uint16
+	tms     uint64
+	entropy uint64
+	rand    io.Reader
+	sync.Mutex
+}
+
+// NewMonotonicSnowflakePro creates a MonotonicSnowflakePro. If rand is nil,
+// crypto/rand.Reader is used.
+func NewMonotonicSnowflakePro(nodeID uint16, rand io.Reader) (*MonotonicSnowflakePro, error) {
+	if rand == nil {
+		rand = cryptoRand.Reader
+	}
+
+	return &MonotonicSnowflakePro{
+		node: nodeID,
+		rand: rand,
+	}, nil
+}
+
+func (s *MonotonicSnowflakePro) until(tms int64) {
+	dur := tms - time.Now().UnixMilli()
+	for dur > 0 {
+		time.Sleep(time.Duration(dur) * time.Millisecond)
+		dur = tms - time.Now().UnixMilli()
+	}
+}
+
+// randEntropy reads a fresh entropyBits-wide random value.
+func (s *MonotonicSnowflakePro) randEntropy() uint64 {
+	var b [8]byte
+	if _, err := io.ReadFull(s.rand, b[:]); err != nil {
+		panic(err) // rand.Reader is not expected to fail
+	}
+	return binary.BigEndian.Uint64(b[:])
+}
+
+// randIncrement returns a random step in [1, maxEntropyIncrement].
+func (s *MonotonicSnowflakePro) randIncrement() uint64 {
+	var b [2]byte
+	if _, err := io.ReadFull(s.rand, b[:]); err != nil {
+		panic(err)
+	}
+	return uint64(binary.BigEndian.Uint16(b[:])) + 1
+}
+
+func (s *MonotonicSnowflakePro) Next() SFID {
+	var sfid SFID
+	sfid.SetNode(s.node)
+
+	s.Lock()
+	now := uint64(time.Now().UnixMilli())
+	if now <= s.tms {
+		inc := s.randIncrement()
+		if s.entropy+inc < s.entropy { // overflowed the 64bit entropy space
+			s.tms++
+			s.until(int64(s.tms))
+			s.entropy = s.randEntropy()
+		} else {
+			s.entropy += inc
+		}
+	} else {
+		s.tms = now
+		s.entropy = s.randEntropy()
+	}
+	tms := s.tms
+	entropy := s.entropy
+	s.Unlock()
+
+	sfid.SetTime(tms)
+	sfid.SetNonce(entropy >> 24)
+	sfid.SetSN(uint32(entropy) & SNMask)
+	return sfid
+}
@@ -0,0 +1,18 @@
+package snowflakepro
+
+import "testing"
+
+func Test_MonotonicNext(t *testing.T) {
+	s, _ := NewMonotonicSnowflakePro(100, nil)
+	var prev SFID
+	for i := 0; i < 1000; i++ {
+		id := s.Next()
+		if i > 0 && id.Compare(prev) <= 0 {
+			t.Fatal("monotonic order error", id, prev)
+		}
+		if id.Node() != s.node {
+			t.Fatal("node error")
+		}
+		prev = id
+	}
+}
@@ -0,0 +1,125 @@
+package snowflakepro
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_ClockRollbackPolicyError(t *testing.T) {
+	s, _ := NewSnowflakePro(100, 1, WithClockRollbackPolicy(PolicyError, 0))
+	s.tms = uint64(time.Now().UnixMilli()) + 10_000 // simulate a future tms
+
+	if _, err := s.NextE(); err != ErrClockRollback {
+		t.Fatal("expected ErrClockRollback, got", err)
+	}
+}
+
+func Test_ClockRollbackPolicyBorrow(t *testing.T) {
+	s, _ := NewSnowflakePro(100, 1, WithClockRollbackPolicy(PolicyBorrow, 0))
+	s.tms = uint64(time.Now().UnixMilli()) + 10
+
+	id, err := s.NextE()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id.Time() != s.tms {
+		t.Fatal("expected id to keep issuing from the borrowed tms")
+	}
+	if s.BorrowedMilliseconds() == 0 {
+		t.Fatal("expected borrowed milliseconds to be recorded")
+	}
+}
+
+func Test_ClockRollbackPolicyWaitBlocksThenSucceeds(t *testing.T) {
+	s, _ := NewSnowflakePro(100, 1, WithClockRollbackPolicy(PolicyWait, time.Second))
+	future := uint64(time.Now().UnixMilli()) + 50
+	s.tms = future
+
+	start := time.Now()
+	id, err := s.NextE()
+	elapsed := time.Since(start)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id.Time() != future {
+		t.Fatal("expected id to wait for the borrowed tms", id.Time(), future)
+	}
+	if elapsed < 40*time.Millisecond {
+		t.Fatal("expected NextE to block until the wall clock caught up", elapsed)
+	}
+}
+
+func Test_ClockRollbackPolicyWaitTimesOut(t *testing.T) {
+	s, _ := NewSnowflakePro(100, 1, WithClockRollbackPolicy(PolicyWait, 10*time.Millisecond))
+	s.tms = uint64(time.Now().UnixMilli()) + 10_000
+
+	start := time.Now()
+	_, err := s.NextE()
+	elapsed := time.Since(start)
+
+	if err != ErrClockRollback {
+		t.Fatal("expected ErrClockRollback, got", err)
+	}
+	if elapsed > time.Second {
+		t.Fatal("expected NextE to give up around MaxWait instead of blocking for the full rollback", elapsed)
+	}
+}
+
+func Test_ClockRollbackDefaultPolicyAbsorbsLikeBefore(t *testing.T) {
+	s, _ := NewSnowflakePro(100, 1) // no WithClockRollbackPolicy: must behave exactly as before NextE existed
+	s.tms = uint64(time.Now().UnixMilli()) + 10_000
+
+	id, err := s.NextE()
+	if err != nil {
+		t.Fatal("default policy must never return an error", err)
+	}
+	if id.Time() != s.tms {
+		t.Fatal("expected the rollback to be silently absorbed", id.Time(), s.tms)
+	}
+}
+
+type countingTimestampStore struct {
+	MemoryTimestampStore
+	saves int
+}
+
+func (c *countingTimestampStore) Save(tms uint64) error {
+	c.saves++
+	return c.MemoryTimestampStore.Save(tms)
+}
+
+func Test_TimestampStoreOnlySavedWhenTmsAdvances(t *testing.T) {
+	store := &countingTimestampStore{}
+	s, _ := NewSnowflakePro(100, 1, WithTimestampStore(store))
+	s.tms = uint64(time.Now().UnixMilli()) + 10_000 // pin tms so every call below lands in the same ms
+
+	for i := 0; i < 5; i++ {
+		if _, err := s.NextE(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if store.saves != 0 {
+		t.Fatalf("expected no store writes while tms stays the same, got %d", store.saves)
+	}
+}
+
+func Test_TimestampStoreSeedsAndPersists(t *testing.T) {
+	store := &MemoryTimestampStore{}
+	store.Save(12345)
+
+	s, err := NewSnowflakePro(100, 1, WithTimestampStore(store))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.tms != 12345 {
+		t.Fatal("expected tms to be seeded from the store", s.tms)
+	}
+
+	id := s.Next()
+	saved, _ := store.Load()
+	if saved != id.Time() {
+		t.Fatal("expected store to be updated with the issued tms")
+	}
+}
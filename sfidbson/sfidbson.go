@@ -0,0 +1,80 @@
+// Package sfidbson adds BSON and MongoDB ObjectID interop for SFID. It is
+// kept as a separate package so that importing the core snowflakepro
+// package never pulls in the mongo driver.
+package sfidbson
+
+import (
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/x/bsonx/bsoncore"
+
+	snowflakepro "github.com/xsean2020/snowflakepro-go"
+)
+
+// SFID wraps snowflakepro.SFID to implement bson.ValueMarshaler and
+// bson.ValueUnmarshaler, round-tripping as a BSON binary subtype 0x04
+// (UUID) field.
+type SFID snowflakepro.SFID
+
+// MarshalBSONValue implements bson.ValueMarshaler.
+func (id SFID) MarshalBSONValue() (bsontype.Type, []byte, error) {
+	return bsontype.Binary, bsoncore.AppendBinary(nil, 0x04, id[:]), nil
+}
+
+// UnmarshalBSONValue implements bson.ValueUnmarshaler.
+func (id *SFID) UnmarshalBSONValue(t bsontype.Type, data []byte) error {
+	if t != bsontype.Binary {
+		return snowflakepro.ErrDataSize
+	}
+	subtype, bin, _, ok := bsoncore.ReadBinary(data)
+	if !ok || subtype != 0x04 || len(bin) != len(*id) {
+		return snowflakepro.ErrDataSize
+	}
+	copy((*id)[:], bin)
+	return nil
+}
+
+// FromObjectID converts a MongoDB ObjectID into a SFID, preserving the
+// time prefix: the 4 byte ObjectID second-resolution timestamp becomes the
+// SFID's 6 byte millisecond timestamp, and the remaining 8 bytes of
+// machine/process/counter entropy are packed into the nonce and sn fields
+// (the node field is left zero, since ObjectID carries no node concept).
+func FromObjectID(oid primitive.ObjectID) SFID {
+	var id snowflakepro.SFID
+	seconds := uint64(oid[0])<<24 | uint64(oid[1])<<16 | uint64(oid[2])<<8 | uint64(oid[3])
+	id.SetTime(seconds * 1000)
+
+	nonce := uint64(oid[4])<<32 | uint64(oid[5])<<24 | uint64(oid[6])<<16 | uint64(oid[7])<<8 | uint64(oid[8])
+	id.SetNonce(nonce)
+
+	sn := uint32(oid[9])<<16 | uint32(oid[10])<<8 | uint32(oid[11])
+	id.SetSN(sn)
+
+	return SFID(id)
+}
+
+// ToObjectID converts a SFID back into a MongoDB ObjectID. The millisecond
+// timestamp is truncated to seconds and the nonce/sn fields are packed
+// back into the 8 remaining ObjectID bytes; the node field is discarded.
+func (id SFID) ToObjectID() primitive.ObjectID {
+	var oid primitive.ObjectID
+	seconds := uint32(snowflakepro.SFID(id).Time() / 1000)
+	oid[0] = byte(seconds >> 24)
+	oid[1] = byte(seconds >> 16)
+	oid[2] = byte(seconds >> 8)
+	oid[3] = byte(seconds)
+
+	nonce := snowflakepro.SFID(id).Nonce()
+	oid[4] = byte(nonce >> 32)
+	oid[5] = byte(nonce >> 24)
+	oid[6] = byte(nonce >> 16)
+	oid[7] = byte(nonce >> 8)
+	oid[8] = byte(nonce)
+
+	sn := snowflakepro.SFID(id).SN()
+	oid[9] = byte(sn >> 16)
+	oid[10] = byte(sn >> 8)
+	oid[11] = byte(sn)
+
+	return oid
+}
@@ -0,0 +1,49 @@
+package sfidbson
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	snowflakepro "github.com/xsean2020/snowflakepro-go"
+)
+
+func Test_BSONRoundTrip(t *testing.T) {
+	s, _ := snowflakepro.NewSnowflakePro(100, 42)
+
+	type doc struct {
+		ID SFID
+	}
+	in := doc{ID: SFID(s.Next())}
+
+	raw, err := bson.Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out doc
+	if err := bson.Unmarshal(raw, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.ID != in.ID {
+		t.Fatal("bson round trip mismatch", out.ID, in.ID)
+	}
+}
+
+func Test_ObjectIDRoundTrip(t *testing.T) {
+	s, _ := snowflakepro.NewSnowflakePro(100, 42)
+	id := SFID(s.Next())
+
+	oid := id.ToObjectID()
+	got := FromObjectID(oid)
+
+	if snowflakepro.SFID(got).Time()/1000 != snowflakepro.SFID(id).Time()/1000 {
+		t.Fatal("time prefix not preserved", got, id)
+	}
+	if snowflakepro.SFID(got).Nonce() != snowflakepro.SFID(id).Nonce() {
+		t.Fatal("nonce not preserved")
+	}
+	if snowflakepro.SFID(got).SN() != snowflakepro.SFID(id).SN() {
+		t.Fatal("sn not preserved")
+	}
+}
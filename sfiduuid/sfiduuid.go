@@ -0,0 +1,20 @@
+// Package sfiduuid adds github.com/google/uuid interop for SFID. It is
+// kept as a separate package so that importing the core snowflakepro
+// package never pulls in the uuid dependency.
+package sfiduuid
+
+import (
+	"github.com/google/uuid"
+
+	snowflakepro "github.com/xsean2020/snowflakepro-go"
+)
+
+// UUID converts a SFID into a uuid.UUID, preserving the raw 16 bytes.
+func UUID(id snowflakepro.SFID) uuid.UUID {
+	return uuid.UUID(id)
+}
+
+// FromUUID converts a uuid.UUID into a SFID, preserving the raw 16 bytes.
+func FromUUID(u uuid.UUID) snowflakepro.SFID {
+	return snowflakepro.SFID(u)
+}
@@ -0,0 +1,134 @@
+package snowflakepro
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// shardBits is the number of low bits of the 24bit SN field reserved for the
+// shard index. Packing the shard index into the SN field lets every shard
+// mint IDs independently without ever colliding with another shard's
+// (tms, sn) pair.
+const shardBits = 4
+
+// MaxShards is the largest shard count NewShardedSnowflakePro accepts.
+const MaxShards = 1 << shardBits
+
+const shardSNMask = SNMask >> shardBits
+
+// counterBits is the width of the per-shard counter packed into shard.word
+// alongside tms. It is the 24bit SN field minus the bits reserved for the
+// shard index, so the packed counter plus the shard index fill the SN
+// field exactly once unpacked in build().
+//
+// Packing tms and the counter into a single uint64 word only works while
+// tms fits in 64-counterBits bits; at counterBits=20 that's 44 bits of
+// milliseconds, good until roughly the year 2527, which is why the word is
+// packed this way instead of shifting tms by the full 24 bits of the SN
+// field (a 41+ bit tms, true since ~2004, would silently overflow a
+// tms<<24 word).
+const counterBits = 24 - shardBits
+
+var ErrBigShards = errors.New("SFID: shards too big")
+
+// shard holds one independent (tms, sn) counter packed into a single word
+// so the common case can be advanced with a CompareAndSwap instead of a
+// mutex.
+type shard struct {
+	word atomic.Uint64 // packed (tms<<counterBits | sn)
+	mu   sync.Mutex
+}
+
+// ShardedSnowflakePro is a SnowflakePro variant that spreads allocations
+// across N independent shards to avoid a single mutex becoming the
+// bottleneck under contention. Shards are selected round-robin via an
+// atomic cursor, and the shard index is packed into the low bits of the SN
+// field so IDs minted by different shards never collide.
+type ShardedSnowflakePro struct {
+	node   uint16
+	nonce  uint64
+	shards []shard
+	cursor atomic.Uint32
+}
+
+// NewShardedSnowflakePro creates a ShardedSnowflakePro with the given number
+// of shards. shards must be in (0, MaxShards].
+func NewShardedSnowflakePro(nodeID uint16, nonce uint64, shards int) (*ShardedSnowflakePro, error) {
+	if nonce > MaxNonce {
+		return nil, ErrBigNonce
+	}
+	if shards <= 0 || shards > MaxShards {
+		return nil, ErrBigShards
+	}
+
+	return &ShardedSnowflakePro{
+		node:   nodeID,
+		nonce:  nonce,
+		shards: make([]shard, shards),
+	}, nil
+}
+
+func (s *ShardedSnowflakePro) until(tms int64) {
+	dur := tms - time.Now().UnixMilli()
+	for dur > 0 {
+		time.Sleep(time.Duration(dur) * time.Millisecond)
+		dur = tms - time.Now().UnixMilli()
+	}
+}
+
+// Next returns the next SFID. g.word is only ever advanced through
+// CompareAndSwap, including on the sequence-exhausted slow path below, so
+// the fast path and the slow path can never both succeed in writing the
+// same (tms, sn) pair: CompareAndSwap guarantees that of any two callers
+// racing on the same observed word, at most one wins. g.mu is taken only
+// to stop every exhausted caller from sleeping independently in until();
+// it does not by itself guard the word, which is why the slow path still
+// verifies with a CAS instead of an unconditional Store.
+func (s *ShardedSnowflakePro) Next() SFID {
+	idx := s.cursor.Add(1) % uint32(len(s.shards))
+	g := &s.shards[idx]
+
+	for {
+		old := g.word.Load()
+		oldTms := old >> counterBits
+		now := uint64(time.Now().UnixMilli())
+
+		if now > oldTms {
+			if g.word.CompareAndSwap(old, now<<counterBits) {
+				return s.build(uint16(idx), now, 0)
+			}
+			continue
+		}
+
+		sn := (uint32(old&shardSNMask) + 1) & shardSNMask
+		if sn != 0 {
+			if g.word.CompareAndSwap(old, (oldTms<<counterBits)|uint64(sn)) {
+				return s.build(uint16(idx), oldTms, sn)
+			}
+			continue
+		}
+
+		// This shard's sequence space for oldTms is exhausted. Bump tms
+		// to oldTms+1, sleeping if the wall clock hasn't reached it yet.
+		g.mu.Lock()
+		if g.word.Load() != old {
+			g.mu.Unlock()
+			continue // another goroutine already advanced past this word
+		}
+		next := oldTms + 1
+		s.until(int64(next))
+		g.word.CompareAndSwap(old, next<<counterBits)
+		g.mu.Unlock()
+	}
+}
+
+func (s *ShardedSnowflakePro) build(idx uint16, tms uint64, sn uint32) SFID {
+	var sfid SFID
+	sfid.SetNode(s.node)
+	sfid.SetNonce(s.nonce)
+	sfid.SetTime(tms)
+	sfid.SetSN((sn << shardBits) | uint32(idx))
+	return sfid
+}
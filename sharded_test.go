@@ -0,0 +1,40 @@
+package snowflakepro
+
+import (
+	"sync"
+	"testing"
+)
+
+func Test_ShardedNextConcurrentUnique(t *testing.T) {
+	const goroutines = 64
+	const perGoroutine = 2000
+
+	// A single shard maximizes contention between the fast CAS path and
+	// the mutex-guarded slow path, which is where duplicates would show up.
+	s, err := NewShardedSnowflakePro(100, 42, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ids := make(chan SFID, goroutines*perGoroutine)
+	var wg sync.WaitGroup
+	wg.Add(goroutines)
+	for i := 0; i < goroutines; i++ {
+		go func() {
+			defer wg.Done()
+			for j := 0; j < perGoroutine; j++ {
+				ids <- s.Next()
+			}
+		}()
+	}
+	wg.Wait()
+	close(ids)
+
+	seen := make(map[SFID]struct{}, goroutines*perGoroutine)
+	for id := range ids {
+		if _, dup := seen[id]; dup {
+			t.Fatalf("duplicate SFID emitted under contention: %v", id)
+		}
+		seen[id] = struct{}{}
+	}
+}
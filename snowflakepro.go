@@ -1,7 +1,9 @@
 package snowflakepro
 
 import (
+	"errors"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -9,23 +11,113 @@ const (
 	SNMask = 0xFFFFFF // 24bit  16777215
 )
 
+// ErrClockRollback is returned by NextE when the wall clock has jumped
+// backward (NTP correction, VM migration, leap-second smear) and the
+// configured ClockRollbackPolicy is PolicyError, or PolicyWait's MaxWait
+// has been exceeded.
+var ErrClockRollback = errors.New("SFID: clock rollback detected")
+
+// ClockRollbackPolicy controls how a SnowflakePro reacts when
+// time.Now().UnixMilli() is observed to be behind the last issued
+// timestamp.
+type ClockRollbackPolicy int
+
+const (
+	// PolicyAbsorb is the zero value and the default when no
+	// WithClockRollbackPolicy option is given: a rollback is treated
+	// exactly like same-millisecond traffic always was before NextE
+	// existed, i.e. s.sn just keeps incrementing. This keeps every
+	// pre-existing caller's behavior unchanged; callers must opt in to
+	// PolicyWait, PolicyBorrow or PolicyError to get rollback detection.
+	PolicyAbsorb ClockRollbackPolicy = iota
+
+	// PolicyWait blocks in until() until wall time catches up to s.tms. If
+	// the rollback is larger than MaxWait, NextE returns ErrClockRollback
+	// at once without waiting. MaxWait of 0 waits indefinitely.
+	PolicyWait
+
+	// PolicyBorrow keeps issuing IDs without waiting: it continues s.tms's
+	// sequence and moves on to s.tms+1 when that sequence is exhausted. On
+	// every such call it adds how far s.tms is ahead of the wall clock to
+	// the total reported by BorrowedMilliseconds.
+	PolicyBorrow
+
+	// PolicyError fails fast with ErrClockRollback as soon as a rollback is
+	// observed.
+	PolicyError
+)
+
+// TimestampStore persists the highest tms a SnowflakePro has issued, so a
+// restarted process never re-uses timestamps after a clock rollback.
+type TimestampStore interface {
+	// Load returns the last persisted tms, or 0 if none was ever saved.
+	Load() (uint64, error)
+	// Save persists tms as the highest tms issued so far.
+	Save(tms uint64) error
+}
+
+// Option configures a SnowflakePro at construction time.
+type Option func(*SnowflakePro)
+
+// WithClockRollbackPolicy sets the policy applied when a clock rollback is
+// detected, and the maximum duration PolicyWait will block for before
+// giving up. maxWait is ignored by PolicyBorrow and PolicyError.
+func WithClockRollbackPolicy(policy ClockRollbackPolicy, maxWait time.Duration) Option {
+	return func(s *SnowflakePro) {
+		s.rollbackPolicy = policy
+		s.maxWait = maxWait
+	}
+}
+
+// WithTimestampStore attaches a TimestampStore a SnowflakePro uses to seed
+// its initial tms and to persist every tms it issues.
+func WithTimestampStore(store TimestampStore) Option {
+	return func(s *SnowflakePro) {
+		s.store = store
+	}
+}
+
 type SnowflakePro struct {
 	sn    uint32
 	node  uint16
 	nonce uint64
 	tms   uint64
 	sync.Mutex
+
+	rollbackPolicy ClockRollbackPolicy
+	maxWait        time.Duration
+	store          TimestampStore
+	borrowedMs     atomic.Uint64
 }
 
-func NewSnowflakePro(nodeid uint16, nonce uint64) (*SnowflakePro, error) {
+func NewSnowflakePro(nodeid uint16, nonce uint64, opts ...Option) (*SnowflakePro, error) {
 	if nonce > MaxNonce {
 		return nil, ErrBigNonce
 	}
 
-	return &SnowflakePro{
+	s := &SnowflakePro{
 		nonce: nonce,
 		node:  nodeid,
-	}, nil
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	if s.store != nil {
+		tms, err := s.store.Load()
+		if err != nil {
+			return nil, err
+		}
+		s.tms = tms
+		if tms > 0 {
+			// A previous process may already have issued IDs at tms, so
+			// treat its sequence as exhausted: the next ID at or before
+			// tms moves on to tms+1.
+			s.sn = SNMask
+		}
+	}
+
+	return s, nil
 }
 
 func (s *SnowflakePro) until(tms int64) {
@@ -36,13 +128,70 @@ func (s *SnowflakePro) until(tms int64) {
 	}
 }
 
+// save persists tms via the configured TimestampStore, if any. Callers
+// should only invoke this when tms has actually advanced: saving on every
+// same-millisecond sn bump would serialize every NextE call behind a
+// TimestampStore write (e.g. a synchronous file write for
+// FileTimestampStore), undoing the whole point of a fast generator.
+func (s *SnowflakePro) save(tms uint64) error {
+	if s.store == nil {
+		return nil
+	}
+	return s.store.Save(tms)
+}
+
+// BorrowedMilliseconds returns the running total, over every NextE call
+// that PolicyBorrow served during an observed clock rollback, of how many
+// milliseconds s.tms was ahead of the wall clock at that call.
+func (s *SnowflakePro) BorrowedMilliseconds() uint64 {
+	return s.borrowedMs.Load()
+}
+
+// Next returns the next SFID. It is equivalent to NextE with the error
+// dropped: when NextE fails (a rejected clock rollback or a TimestampStore
+// error), Next returns the zero SFID. Callers that need to react to a
+// detected rollback should use NextE instead.
 func (s *SnowflakePro) Next() SFID {
+	sfid, _ := s.NextE()
+	return sfid
+}
+
+// NextE returns the next SFID, or ErrClockRollback if a clock rollback was
+// detected and the configured ClockRollbackPolicy rejected the request
+// (PolicyError, or PolicyWait exceeding MaxWait).
+func (s *SnowflakePro) NextE() (SFID, error) {
 	var sfid SFID
 	sfid.SetNode(s.node)
 	sfid.SetNonce(s.nonce)
+
 	s.Lock()
+	prevTms := s.tms
 	now := uint64(time.Now().UnixMilli())
-	if now <= s.tms { // 同一时刻产生的序列
+	if now < s.tms && s.rollbackPolicy != PolicyAbsorb {
+		switch s.rollbackPolicy {
+		case PolicyError:
+			s.Unlock()
+			return SFID{}, ErrClockRollback
+
+		case PolicyBorrow:
+			s.borrowedMs.Add(s.tms - now)
+			s.sn = (s.sn + 1) & SNMask
+			if s.sn == 0 {
+				s.tms++
+			}
+
+		default: // PolicyWait
+			if s.maxWait > 0 && time.Duration(s.tms-now)*time.Millisecond > s.maxWait {
+				s.Unlock()
+				return SFID{}, ErrClockRollback
+			}
+			s.until(int64(s.tms))
+			s.sn = (s.sn + 1) & SNMask
+			if s.sn == 0 {
+				s.tms++
+			}
+		}
+	} else if now <= s.tms { // 同一时刻产生的序列 (includes an absorbed rollback)
 		s.sn = (s.sn + 1) & SNMask
 		if s.sn == 0 {
 			s.tms++
@@ -54,8 +203,16 @@ func (s *SnowflakePro) Next() SFID {
 	}
 	tms := s.tms
 	sn := s.sn
+	var err error
+	if tms != prevTms {
+		err = s.save(tms)
+	}
 	s.Unlock()
+	if err != nil {
+		return SFID{}, err
+	}
+
 	sfid.SetTime(tms)
 	sfid.SetSN(sn)
-	return sfid
+	return sfid, nil
 }
@@ -2,6 +2,7 @@ package snowflakepro
 
 import (
 	"math/rand"
+	"runtime"
 	"testing"
 )
 
@@ -35,3 +36,17 @@ func Benchmark_next(b *testing.B) {
 		s.Next()
 	}
 }
+
+func Benchmark_ShardedNext(b *testing.B) {
+	shards := runtime.GOMAXPROCS(0)
+	if shards > MaxShards {
+		shards = MaxShards
+	}
+	var s, _ = NewShardedSnowflakePro(65000, uint64(rand.Uint32()), shards)
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			s.Next()
+		}
+	})
+}
@@ -0,0 +1,92 @@
+package snowflakepro
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+)
+
+// MemoryTimestampStore is a TimestampStore backed by an in-process value.
+// It is mainly useful for tests, since it offers no protection across
+// process restarts.
+type MemoryTimestampStore struct {
+	mu  sync.Mutex
+	tms uint64
+}
+
+func (m *MemoryTimestampStore) Load() (uint64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.tms, nil
+}
+
+func (m *MemoryTimestampStore) Save(tms uint64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.tms = tms
+	return nil
+}
+
+// FileTimestampStore is a TimestampStore that persists the last issued tms
+// to a file, so a restarted process can recover it across restarts.
+type FileTimestampStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileTimestampStore creates a FileTimestampStore backed by path. The
+// file is created on first Save if it does not already exist.
+func NewFileTimestampStore(path string) *FileTimestampStore {
+	return &FileTimestampStore{path: path}
+}
+
+func (f *FileTimestampStore) Load() (uint64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	data, err := os.ReadFile(f.path)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	tms, err := strconv.ParseUint(string(data), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("SFID: corrupt timestamp store %q: %w", f.path, err)
+	}
+	return tms, nil
+}
+
+// Save writes tms via a temp file plus rename, so a crash or kill mid-write
+// can never leave f.path holding a truncated-but-still-numeric value that
+// Load would silently accept as a smaller, stale tms.
+func (f *FileTimestampStore) Save(tms uint64) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	tmp, err := os.CreateTemp(filepath.Dir(f.path), filepath.Base(f.path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.WriteString(strconv.FormatUint(tms, 10)); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), f.path)
+}
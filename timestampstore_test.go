@@ -0,0 +1,55 @@
+package snowflakepro
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func Test_FileTimestampStoreRoundTrip(t *testing.T) {
+	store := NewFileTimestampStore(filepath.Join(t.TempDir(), "tms"))
+
+	tms, err := store.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tms != 0 {
+		t.Fatal("expected 0 before any Save", tms)
+	}
+
+	if err := store.Save(1785088774067); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := store.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != 1785088774067 {
+		t.Fatal("expected the saved tms back", got)
+	}
+}
+
+func Test_FileTimestampStoreSaveIsAtomic(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tms")
+	store := NewFileTimestampStore(path)
+
+	if err := store.Save(500123456); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := filepath.Glob(filepath.Join(filepath.Dir(path), "*"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0] != path {
+		t.Fatalf("expected Save to leave only the final file behind, got %v", entries)
+	}
+
+	got, err := store.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != 500123456 {
+		t.Fatal("expected the full value, not a truncated one", got)
+	}
+}